@@ -1,10 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ian-howell/implicit-interfacing/logging"
 )
 
 // User represents a user from the API
@@ -12,22 +19,353 @@ type User struct {
 	Name string `json:"name"`
 }
 
-// HTTPClient interface defines the methods we need from http.Client
-// This is the key to testability - we define a minimal interface
+// Doer is the minimal, context-aware interface we need from an HTTP client.
+// Unlike the Get-only HTTPClient below, Do takes a context explicitly so
+// that callers (including retry wrappers) can cancel an in-flight request
+// or the sleep between retries.
+type Doer interface {
+	Do(ctx context.Context, req *http.Request) (*http.Response, error)
+}
+
+// httpClientDoer adapts a *http.Client to the Doer interface.
+type httpClientDoer struct {
+	client *http.Client
+}
+
+func (d *httpClientDoer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return d.client.Do(req.WithContext(ctx))
+}
+
+// HTTPClient is the original, context-unaware interface GetUser used before
+// it adopted Doer. It's kept for callers that only have a plain Get method
+// available; use AdaptHTTPClient to use one as a Doer.
 type HTTPClient interface {
 	Get(url string) (*http.Response, error)
 }
 
-// GetUser fetches a user by ID from the API
-func GetUser(client HTTPClient, id int) (User, error) {
+// httpClientAdapter adapts an HTTPClient to the Doer interface.
+type httpClientAdapter struct {
+	client HTTPClient
+}
+
+// AdaptHTTPClient wraps client, a legacy Get-only HTTPClient, as a Doer so
+// it can be used anywhere a Doer is expected. Because HTTPClient has no way
+// to accept a context, ctx's deadline/cancellation isn't honored for
+// requests issued this way.
+func AdaptHTTPClient(client HTTPClient) Doer {
+	return &httpClientAdapter{client: client}
+}
+
+func (a *httpClientAdapter) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return a.client.Get(req.URL.String())
+}
+
+// Sleeper abstracts time.Sleep so that retry backoff delays can be faked in
+// tests. It has the same shape as the Sleeper interface in the sleeper demo;
+// it's declared separately here because each package only depends on the
+// methods it actually calls.
+type Sleeper interface {
+	Sleep(duration time.Duration)
+}
+
+type realSleeper struct{}
+
+func (realSleeper) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// BackoffPolicy computes how long to wait before the next retry attempt.
+// attempt is zero-indexed: 0 is the delay before the first retry.
+type BackoffPolicy interface {
+	Backoff(attempt int) time.Duration
+}
+
+// ConstantBackoff always waits the same duration between retries.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Backoff(attempt int) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff implements full-jitter exponential backoff:
+// delay = min(cap, base * 2^attempt), then a uniformly random duration in
+// [0, delay) is returned so that many clients retrying at once don't
+// synchronize.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+}
+
+func (b ExponentialBackoff) Backoff(attempt int) time.Duration {
+	delay := b.Base << attempt
+	if delay <= 0 || delay > b.Cap {
+		delay = b.Cap
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" algorithm:
+// sleep = min(cap, base + random_between(0, prev*3)). It carries the previous
+// delay between calls, so a single DecorrelatedJitterBackoff must not be
+// shared across concurrent retry sequences.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	prev time.Duration
+}
+
+func (b *DecorrelatedJitterBackoff) Backoff(attempt int) time.Duration {
+	prev := b.prev
+	if prev <= 0 {
+		prev = b.Base
+	}
+	if prev <= 0 {
+		return 0
+	}
+
+	delay := rand.Int63n(3*int64(prev)) + int64(b.Base)
+	if delay > int64(b.Cap) {
+		delay = int64(b.Cap)
+	}
+
+	b.prev = time.Duration(delay)
+	return b.prev
+}
+
+// RetryPolicy decides whether a request should be retried given the
+// response and/or error from the last attempt.
+type RetryPolicy interface {
+	// ShouldRetry reports whether the caller should retry, and if the
+	// response demands a specific wait (e.g. Retry-After), how long that is.
+	// A zero after means "defer to the BackoffPolicy".
+	ShouldRetry(resp *http.Response, err error, attempt int) (retry bool, after time.Duration)
+}
+
+// StatusRetryPolicy retries on network errors, 5xx responses, and 429 Too
+// Many Requests (honoring Retry-After when present). It never retries other
+// 4xx responses.
+type StatusRetryPolicy struct {
+	MaxAttempts int
+}
+
+func (p StatusRetryPolicy) ShouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= p.MaxAttempts {
+		return false, 0
+	}
+
+	if err != nil {
+		return true, 0
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true, parseRetryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= 500:
+		return true, 0
+	default:
+		return false, 0
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which per RFC 9110 is either
+// a number of seconds or an HTTP-date. It returns zero if the header is
+// absent or malformed, leaving the decision to the BackoffPolicy.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// RetryClient wraps a Doer, retrying failed requests according to a
+// RetryPolicy and waiting between attempts according to a BackoffPolicy.
+type RetryClient struct {
+	doer    Doer
+	backoff BackoffPolicy
+	retry   RetryPolicy
+	sleeper Sleeper
+	logger  logging.Logger
+}
+
+// RetryClientOption configures a RetryClient constructed via
+// NewRetryClient.
+type RetryClientOption func(*RetryClient)
+
+// WithRetryLogger configures the Logger a RetryClient uses to emit
+// http.retry events, overridden per call by any Logger attached to Do's
+// context.
+func WithRetryLogger(logger logging.Logger) RetryClientOption {
+	return func(c *RetryClient) { c.logger = logger }
+}
+
+// NewRetryClient constructs a RetryClient that wraps doer with the given
+// retry and backoff policies, sleeping between attempts via sleeper.
+func NewRetryClient(doer Doer, backoff BackoffPolicy, retry RetryPolicy, sleeper Sleeper, opts ...RetryClientOption) *RetryClient {
+	c := &RetryClient{
+		doer:    doer,
+		backoff: backoff,
+		retry:   retry,
+		sleeper: sleeper,
+		logger:  logging.Noop,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Do issues req, retrying according to c.retry until it succeeds, exhausts
+// retries, or ctx is canceled (including while sleeping between attempts).
+func (c *RetryClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	logger := logging.OrDefault(ctx, c.logger)
+
+	var attempt int
+	for {
+		attemptReq, err := cloneRequestForAttempt(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+
+		resp, err := c.doer.Do(ctx, attemptReq)
+
+		retry, after := c.retry.ShouldRetry(resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if after <= 0 {
+			after = c.backoff.Backoff(attempt)
+		}
+
+		logger.Log(logging.LevelWarn, "http.retry", "attempt", attempt, "after", after, "err", err)
+
+		if err := sleepOrCancel(ctx, c.sleeper, after); err != nil {
+			return nil, err
+		}
+
+		attempt++
+	}
+}
+
+// cloneRequestForAttempt clones req for a single attempt, rewinding its body
+// via GetBody if one is set. req.Clone alone doesn't deep-copy Body (per the
+// net/http docs), so without this, every attempt after the first would send
+// whatever the previous attempt's reader left behind - empty, for a body
+// that's already been read to EOF. Requests built by http.NewRequest(WithContext)
+// with a bytes.Reader/bytes.Buffer/strings.Reader body populate GetBody
+// automatically; a req with no GetBody (e.g. built from an arbitrary
+// io.Reader) is cloned as-is, so retrying such a request still risks
+// sending a truncated body.
+func cloneRequestForAttempt(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+	if req.GetBody == nil {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// sleepOrCancel sleeps for d via sleeper, returning ctx.Err() immediately if
+// ctx is canceled first. sleeper.Sleep itself isn't context-aware, so we race
+// it against ctx.Done in a goroutine.
+func sleepOrCancel(ctx context.Context, sleeper Sleeper, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		sleeper.Sleep(d)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Client fetches users over a Doer, emitting structured log events through
+// an injected Logger.
+type Client struct {
+	doer   Doer
+	logger logging.Logger
+}
+
+// ClientOption configures a Client constructed via NewClient.
+type ClientOption func(*Client)
+
+// WithClientLogger configures the Logger a Client uses to emit http.request
+// and http.response events, overridden per call by any Logger attached to
+// GetUser's context.
+func WithClientLogger(logger logging.Logger) ClientOption {
+	return func(c *Client) { c.logger = logger }
+}
+
+// NewClient constructs a Client that fetches users over doer. By default it
+// emits no log events; pass WithClientLogger to change that.
+func NewClient(doer Doer, opts ...ClientOption) *Client {
+	c := &Client{doer: doer, logger: logging.Noop}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetUser fetches a user by ID from the API, propagating ctx cancellation
+// into the request and (if doer retries internally) into its backoff
+// sleeps, and logging http.request/http.response events through ctx's
+// Logger (if any) or c's configured Logger.
+func (c *Client) GetUser(ctx context.Context, id int) (User, error) {
+	logger := logging.OrDefault(ctx, c.logger)
 	url := fmt.Sprintf("https://jsonplaceholder.typicode.com/users/%d", id)
 
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	logger.Log(logging.LevelInfo, "http.request", "method", req.Method, "url", url)
+
+	resp, err := c.doer.Do(ctx, req)
 	if err != nil {
 		return User{}, fmt.Errorf("failed to fetch user: %w", err)
 	}
 	defer resp.Body.Close()
 
+	logger.Log(logging.LevelInfo, "http.response", "status", resp.StatusCode)
+
 	if resp.StatusCode != http.StatusOK {
 		return User{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -45,8 +383,26 @@ func GetUser(client HTTPClient, id int) (User, error) {
 	return user, nil
 }
 
+// GetUser fetches a user by ID from the API using doer directly, with no
+// logging. It's a thin wrapper around Client for callers that don't need a
+// Logger.
+func GetUser(ctx context.Context, doer Doer, id int) (User, error) {
+	return NewClient(doer).GetUser(ctx, id)
+}
+
 func main() {
-	user, err := GetUser(&http.Client{}, 1)
+	logger := logging.NewStdlibLogger(log.Default())
+
+	doer := NewRetryClient(
+		&httpClientDoer{client: &http.Client{}},
+		ExponentialBackoff{Base: 100 * time.Millisecond, Cap: 5 * time.Second},
+		StatusRetryPolicy{MaxAttempts: 3},
+		realSleeper{},
+		WithRetryLogger(logger),
+	)
+	client := NewClient(doer, WithClientLogger(logger))
+
+	user, err := client.GetUser(context.Background(), 1)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
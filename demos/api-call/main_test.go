@@ -2,14 +2,57 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ian-howell/implicit-interfacing/demos/api-call/testhttp"
+	"github.com/ian-howell/implicit-interfacing/logging"
 )
 
+// mockDoer returns a scripted sequence of responses/errors, one per call to
+// Do, so tests can exercise retry behavior.
+type mockDoer struct {
+	responses []*http.Response
+	errs      []error
+	calls     int
+
+	// bodies records the body of every request Do was called with, read to
+	// EOF, so tests can assert the body survives across retries.
+	bodies []string
+}
+
+func (m *mockDoer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	i := m.calls
+	m.calls++
+
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		m.bodies = append(m.bodies, string(body))
+	} else {
+		m.bodies = append(m.bodies, "")
+	}
+
+	var resp *http.Response
+	var err error
+	if i < len(m.responses) {
+		resp = m.responses[i]
+	}
+	if i < len(m.errs) {
+		err = m.errs[i]
+	}
+	return resp, err
+}
+
+// mockHTTPClient is a scripted HTTPClient, for testing GetUser via
+// AdaptHTTPClient.
 type mockHTTPClient struct {
 	statusCode int
 	body       string
@@ -20,10 +63,38 @@ func (m *mockHTTPClient) Get(url string) (*http.Response, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	return newResponse(m.statusCode, m.body, nil), nil
+}
+
+func newResponse(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
 	return &http.Response{
-		StatusCode: m.statusCode,
-		Body:       io.NopCloser(bytes.NewBufferString(m.body)),
-	}, nil
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     header,
+	}
+}
+
+// mockSleeper records every sleep duration it's asked for, without actually
+// sleeping.
+type mockSleeper struct {
+	durations []time.Duration
+}
+
+func (m *mockSleeper) Sleep(d time.Duration) {
+	m.durations = append(m.durations, d)
+}
+
+// blockingSleeper sleeps until unblock is closed, so tests can assert a
+// RetryClient honors context cancellation mid-sleep.
+type blockingSleeper struct {
+	unblock chan struct{}
+}
+
+func (b *blockingSleeper) Sleep(d time.Duration) {
+	<-b.unblock
 }
 
 func TestGetUser(t *testing.T) {
@@ -57,13 +128,12 @@ func TestGetUser(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			mockClient := &mockHTTPClient{
-				statusCode: tt.statusCode,
-				body:       tt.body,
-				err:        tt.mockError,
+			doer := &mockDoer{
+				responses: []*http.Response{newResponse(tt.statusCode, tt.body, nil)},
+				errs:      []error{tt.mockError},
 			}
 
-			user, err := GetUser(mockClient, 1)
+			user, err := GetUser(context.Background(), doer, 1)
 
 			if tt.expectedErrMsg != "" {
 				assert.ErrorContains(t, err, tt.expectedErrMsg)
@@ -75,3 +145,304 @@ func TestGetUser(t *testing.T) {
 		})
 	}
 }
+
+// TestGetUser_ViaAdaptedHTTPClient exercises GetUser through AdaptHTTPClient,
+// covering callers stuck with a legacy Get-only HTTPClient instead of a Doer.
+func TestGetUser_ViaAdaptedHTTPClient(t *testing.T) {
+	client := &mockHTTPClient{statusCode: http.StatusOK, body: `{"name": "John Doe"}`}
+
+	user, err := GetUser(context.Background(), AdaptHTTPClient(client), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+}
+
+// TestGetUser_RealServer exercises GetUser against an actual httptest
+// server via testhttp, as a counterpart to TestGetUser's method-level
+// mocking: this style catches bugs in how requests are built and how real
+// *http.Response values are consumed, at the cost of being slower to write.
+func TestGetUser_RealServer(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		body   string
+
+		expectedName   string
+		expectedErrMsg string
+	}{
+		"success": {
+			status:       http.StatusOK,
+			body:         `{"name": "John Doe"}`,
+			expectedName: "John Doe",
+		},
+		"non-OK status code": {
+			status:         http.StatusNotFound,
+			expectedErrMsg: "unexpected status code: 404",
+		},
+		"invalid JSON": {
+			status:         http.StatusOK,
+			body:           "invalid json",
+			expectedErrMsg: "failed to parse user",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			harness := testhttp.NewHarness(t, []testhttp.Expectation{
+				{Method: http.MethodGet, PathPattern: "/users/*", Status: tt.status, Body: tt.body},
+			})
+
+			user, err := GetUser(context.Background(), harness.Doer(), 1)
+
+			if tt.expectedErrMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, user.Name)
+			harness.AssertExpectationsMet()
+		})
+	}
+}
+
+// TestGetUser_RealServer_FailsMidRead exercises the "failed to read
+// response" branch, which the method-level mock above can't reach because
+// mockDoer always hands back a complete, in-memory body.
+func TestGetUser_RealServer_FailsMidRead(t *testing.T) {
+	harness := testhttp.NewHarness(t, []testhttp.Expectation{
+		{Method: http.MethodGet, PathPattern: "/users/*", Status: http.StatusOK, Body: `{"name"`, FailMidRead: true},
+	})
+
+	_, err := GetUser(context.Background(), harness.Doer(), 1)
+
+	assert.ErrorContains(t, err, "failed to read response")
+}
+
+// TestGetUser_RealServer_SlowResponse exercises Expectation.Delay, faking
+// the wait via an injected Sleeper so the test doesn't actually block.
+func TestGetUser_RealServer_SlowResponse(t *testing.T) {
+	sleeper := &mockSleeper{}
+	harness := testhttp.NewHarness(t, []testhttp.Expectation{
+		{Method: http.MethodGet, PathPattern: "/users/*", Status: http.StatusOK, Body: `{"name": "John Doe"}`, Delay: time.Second},
+	}, testhttp.WithSleeper(sleeper))
+
+	user, err := GetUser(context.Background(), harness.Doer(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, []time.Duration{time.Second}, sleeper.durations)
+}
+
+func TestRetryClient_RetryExhaustion(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{
+			newResponse(http.StatusInternalServerError, "", nil),
+			newResponse(http.StatusInternalServerError, "", nil),
+			newResponse(http.StatusInternalServerError, "", nil),
+		},
+	}
+	sleeper := &mockSleeper{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 2}, sleeper)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, 3, doer.calls)
+	assert.Len(t, sleeper.durations, 2)
+}
+
+func TestRetryClient_SucceedsAfterRetry(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{
+			newResponse(http.StatusServiceUnavailable, "", nil),
+			newResponse(http.StatusOK, `{"name": "John Doe"}`, nil),
+		},
+	}
+	sleeper := &mockSleeper{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 3}, sleeper)
+
+	user, err := GetUser(context.Background(), client, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	assert.Equal(t, 2, doer.calls)
+}
+
+func TestRetryClient_PreservesBodyAcrossRetries(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{
+			newResponse(http.StatusServiceUnavailable, "", nil),
+			newResponse(http.StatusOK, "", nil),
+		},
+	}
+	sleeper := &mockSleeper{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 3}, sleeper)
+
+	const payload = `{"name": "John Doe"}`
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.com", strings.NewReader(payload))
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{payload, payload}, doer.bodies)
+}
+
+func TestRetryClient_NeverRetriesOn4xx(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{newResponse(http.StatusNotFound, "", nil)},
+	}
+	sleeper := &mockSleeper{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 3}, sleeper)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 1, doer.calls)
+	assert.Empty(t, sleeper.durations)
+}
+
+func TestRetryClient_RetriesOnNetworkError(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{nil, newResponse(http.StatusOK, `{"name": "John Doe"}`, nil)},
+		errs:      []error{errors.New("connection reset"), nil},
+	}
+	sleeper := &mockSleeper{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 3}, sleeper)
+
+	user, err := GetUser(context.Background(), client, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+}
+
+func TestRetryClient_ContextCanceledMidSleep(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{newResponse(http.StatusInternalServerError, "", nil)},
+	}
+	sleeper := &blockingSleeper{unblock: make(chan struct{})}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Hour}, StatusRetryPolicy{MaxAttempts: 3}, sleeper)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = client.Do(ctx, req)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, doer.calls)
+}
+
+func TestStatusRetryPolicy_HonorsRetryAfterSeconds(t *testing.T) {
+	resp := newResponse(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{"2"}})
+	policy := StatusRetryPolicy{MaxAttempts: 3}
+
+	retry, after := policy.ShouldRetry(resp, nil, 0)
+
+	assert.True(t, retry)
+	assert.Equal(t, 2*time.Second, after)
+}
+
+func TestStatusRetryPolicy_HonorsRetryAfterHTTPDate(t *testing.T) {
+	when := time.Now().Add(5 * time.Second).UTC()
+	resp := newResponse(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{when.Format(http.TimeFormat)}})
+	policy := StatusRetryPolicy{MaxAttempts: 3}
+
+	retry, after := policy.ShouldRetry(resp, nil, 0)
+
+	assert.True(t, retry)
+	assert.InDelta(t, 5*time.Second, after, float64(time.Second))
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	tests := map[string]struct {
+		backoff DecorrelatedJitterBackoff
+	}{
+		"zero Base and Cap doesn't panic": {
+			backoff: DecorrelatedJitterBackoff{},
+		},
+		"zero Base with a Cap doesn't panic": {
+			backoff: DecorrelatedJitterBackoff{Cap: time.Second},
+		},
+		"non-zero Base and Cap": {
+			backoff: DecorrelatedJitterBackoff{Base: 100 * time.Millisecond, Cap: time.Second},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := tt.backoff
+			for attempt := 0; attempt < 5; attempt++ {
+				delay := b.Backoff(attempt)
+				assert.GreaterOrEqual(t, delay, time.Duration(0))
+				assert.LessOrEqual(t, delay, b.Cap)
+			}
+		})
+	}
+}
+
+func TestClient_GetUser_LogsRequestAndResponse(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{newResponse(http.StatusOK, `{"name": "John Doe"}`, nil)},
+	}
+	logger := &logging.Recorder{}
+	client := NewClient(doer, WithClientLogger(logger))
+
+	user, err := client.GetUser(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+	require.Len(t, logger.Entries, 2)
+	assert.Equal(t, "http.request", logger.Entries[0].Msg)
+	assert.Equal(t, "http.response", logger.Entries[1].Msg)
+	assert.Equal(t, []any{"status", http.StatusOK}, logger.Entries[1].KV)
+}
+
+func TestClient_GetUser_ContextLoggerOverridesClientLogger(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{newResponse(http.StatusOK, `{"name": "John Doe"}`, nil)},
+	}
+	clientLogger := &logging.Recorder{}
+	ctxLogger := &logging.Recorder{}
+	client := NewClient(doer, WithClientLogger(clientLogger))
+
+	_, err := client.GetUser(logging.WithLogger(context.Background(), ctxLogger), 1)
+
+	require.NoError(t, err)
+	assert.Empty(t, clientLogger.Entries)
+	assert.Len(t, ctxLogger.Entries, 2)
+}
+
+func TestRetryClient_LogsRetryEvents(t *testing.T) {
+	doer := &mockDoer{
+		responses: []*http.Response{
+			newResponse(http.StatusInternalServerError, "", nil),
+			newResponse(http.StatusOK, `{"name": "John Doe"}`, nil),
+		},
+	}
+	sleeper := &mockSleeper{}
+	logger := &logging.Recorder{}
+	client := NewRetryClient(doer, ConstantBackoff{Delay: time.Millisecond}, StatusRetryPolicy{MaxAttempts: 3}, sleeper, WithRetryLogger(logger))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), req)
+
+	require.NoError(t, err)
+	require.Len(t, logger.Entries, 1)
+	assert.Equal(t, "http.retry", logger.Entries[0].Msg)
+}
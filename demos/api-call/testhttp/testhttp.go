@@ -0,0 +1,285 @@
+// Package testhttp provides a scripted httptest.Server harness for
+// exercising HTTP client code against a real loopback server, as an
+// alternative to mocking the client interface at the method level.
+package testhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Sleeper abstracts time.Sleep so that Expectation.Delay can be faked in
+// tests instead of actually waiting. It has the same shape as the Sleeper
+// interface in the sleeper demo; it's declared separately here because each
+// package only depends on the methods it actually calls.
+type Sleeper interface {
+	Sleep(duration time.Duration)
+}
+
+type realSleeper struct{}
+
+func (realSleeper) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// Expectation describes one request the harness expects to receive, in
+// order, and the response it should send back.
+type Expectation struct {
+	Method      string
+	PathPattern string // "*" matches a single path segment, e.g. "/users/*"
+	Status      int
+	Body        string
+	Header      http.Header
+	Delay       time.Duration
+
+	// JSONBody, if non-nil, is compared against the request body after
+	// JSON-decoding it, instead of matching PathPattern/Method alone.
+	JSONBody any
+
+	// FailMidRead, if true, sends Status and Body (but not Header) with a
+	// declared Content-Length that exceeds the bytes actually written
+	// before the connection is closed, so callers reading the response
+	// body observe a failure partway through the read.
+	FailMidRead bool
+}
+
+// Harness is a scripted httptest.Server: each incoming request is matched
+// against the next unmatched Expectation, in order.
+type Harness struct {
+	t       testing.TB
+	server  *httptest.Server
+	sleeper Sleeper
+
+	mu           sync.Mutex
+	expectations []Expectation
+	index        int
+	unmatched    []*http.Request
+}
+
+// Option configures a Harness constructed via NewHarness.
+type Option func(*Harness)
+
+// WithSleeper overrides the Sleeper used for Expectation.Delay, letting
+// tests fake slow responses without actually waiting.
+func WithSleeper(sleeper Sleeper) Option {
+	return func(h *Harness) { h.sleeper = sleeper }
+}
+
+// NewHarness starts an httptest.Server that matches incoming requests
+// against expectations in order, calling t.Errorf on any mismatch. The
+// server is closed automatically via t.Cleanup.
+func NewHarness(t testing.TB, expectations []Expectation, opts ...Option) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		t:            t,
+		expectations: expectations,
+		sleeper:      realSleeper{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.server = httptest.NewServer(http.HandlerFunc(h.handle))
+	t.Cleanup(h.server.Close)
+
+	return h
+}
+
+// URL is the base URL of the harness's server.
+func (h *Harness) URL() string {
+	return h.server.URL
+}
+
+// Doer returns a value shaped like the api-call demo's Doer interface
+// (Do(ctx, *http.Request) (*http.Response, error)) that rewrites every
+// request to target the harness's server before sending it. It's returned
+// as a concrete type, not an interface, so this package doesn't need to
+// import the api-call package to satisfy its Doer shape.
+func (h *Harness) Doer() *HarnessDoer {
+	return &HarnessDoer{harness: h}
+}
+
+// HarnessDoer sends requests to its Harness's server regardless of the
+// request's original scheme/host.
+type HarnessDoer struct {
+	harness *Harness
+}
+
+// Do clones req, rewrites it to target the harness's server, and sends it.
+func (d *HarnessDoer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(d.harness.server.URL)
+	if err != nil {
+		return nil, fmt.Errorf("testhttp: parse harness URL: %w", err)
+	}
+
+	req = req.Clone(ctx)
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	req.Host = target.Host
+
+	return d.harness.server.Client().Do(req)
+}
+
+// Unmatched returns the requests received after all expectations were
+// exhausted.
+func (h *Harness) Unmatched() []*http.Request {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.unmatched
+}
+
+// AssertExpectationsMet fails the test if fewer requests were received than
+// expected.
+func (h *Harness) AssertExpectationsMet() {
+	h.t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.index < len(h.expectations) {
+		h.t.Errorf("testhttp: expected %d requests, got %d", len(h.expectations), h.index)
+	}
+}
+
+func (h *Harness) handle(w http.ResponseWriter, r *http.Request) {
+	h.t.Helper()
+
+	h.mu.Lock()
+	idx := h.index
+	h.index++
+	h.mu.Unlock()
+
+	if idx >= len(h.expectations) {
+		h.mu.Lock()
+		h.unmatched = append(h.unmatched, r)
+		h.mu.Unlock()
+		h.t.Errorf("testhttp: unexpected request #%d: %s %s", idx, r.Method, r.URL.Path)
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+		return
+	}
+
+	exp := h.expectations[idx]
+	h.checkExpectation(idx, exp, r)
+
+	if exp.Delay > 0 {
+		h.sleeper.Sleep(exp.Delay)
+	}
+
+	if exp.FailMidRead {
+		h.sendTruncated(w, exp)
+		return
+	}
+
+	for key, values := range exp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(statusOrDefault(exp.Status))
+	io.WriteString(w, exp.Body)
+}
+
+// checkExpectation reports (via t.Errorf) any mismatch between exp and the
+// request actually received.
+func (h *Harness) checkExpectation(idx int, exp Expectation, r *http.Request) {
+	h.t.Helper()
+
+	if exp.Method != "" && r.Method != exp.Method {
+		h.t.Errorf("testhttp: request #%d: expected method %s, got %s", idx, exp.Method, r.Method)
+	}
+
+	if exp.PathPattern != "" && !matchPath(exp.PathPattern, r.URL.Path) {
+		h.t.Errorf("testhttp: request #%d: path %s does not match pattern %s", idx, r.URL.Path, exp.PathPattern)
+	}
+
+	if exp.JSONBody != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.t.Errorf("testhttp: request #%d: failed to read body: %v", idx, err)
+			return
+		}
+
+		got := reflect.New(reflect.TypeOf(exp.JSONBody)).Interface()
+		if err := json.Unmarshal(body, got); err != nil {
+			h.t.Errorf("testhttp: request #%d: failed to unmarshal JSON body: %v", idx, err)
+			return
+		}
+
+		gotValue := reflect.ValueOf(got).Elem().Interface()
+		if !reflect.DeepEqual(exp.JSONBody, gotValue) {
+			h.t.Errorf("testhttp: request #%d: JSON body mismatch: expected %+v, got %+v", idx, exp.JSONBody, gotValue)
+		}
+	}
+}
+
+// sendTruncated hijacks the connection to send a response whose declared
+// Content-Length exceeds the bytes actually written, then closes the
+// connection, so the caller's read of the response body fails partway
+// through instead of completing.
+//
+// It runs on the httptest.Server's handler goroutine, not the Test
+// goroutine, so it reports failures via t.Errorf rather than t.Fatalf:
+// testing.TB documents Fatal/FailNow as callable only from the Test
+// goroutine, since they unwind via runtime.Goexit.
+func (h *Harness) sendTruncated(w http.ResponseWriter, exp Expectation) {
+	h.t.Helper()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		h.t.Errorf("testhttp: ResponseWriter does not support hijacking")
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		h.t.Errorf("testhttp: hijack failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	status := statusOrDefault(exp.Status)
+	fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n", len(exp.Body)+64)
+	buf.WriteString(exp.Body)
+	buf.Flush()
+}
+
+// matchPath reports whether path matches pattern, where a "*" segment in
+// pattern matches any single segment in path.
+func matchPath(pattern, path string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(patternSegs) != len(pathSegs) {
+		return false
+	}
+
+	for i, seg := range patternSegs {
+		if seg == "*" {
+			continue
+		}
+		if seg != pathSegs[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func statusOrDefault(status int) int {
+	if status == 0 {
+		return http.StatusOK
+	}
+	return status
+}
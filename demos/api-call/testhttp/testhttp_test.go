@@ -0,0 +1,105 @@
+package testhttp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userPayload struct {
+	Name string `json:"name"`
+}
+
+// fakeTB wraps a real testing.TB, recording Errorf calls instead of letting
+// them fail the test that's actually running. Embedding TB (rather than
+// implementing the interface directly) satisfies its unexported method,
+// while Helper/Cleanup/etc. still delegate to the real t.
+type fakeTB struct {
+	testing.TB
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) failed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.errors) > 0
+}
+
+func TestExpectation_JSONBody_Matches(t *testing.T) {
+	h := NewHarness(t, []Expectation{
+		{Method: http.MethodPost, PathPattern: "/users", JSONBody: userPayload{Name: "John Doe"}},
+	})
+
+	resp, err := http.Post(h.URL()+"/users", "application/json", strings.NewReader(`{"name": "John Doe"}`))
+	assert.NoError(t, err)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	h.AssertExpectationsMet()
+}
+
+func TestExpectation_JSONBody_MismatchFailsTest(t *testing.T) {
+	fake := &fakeTB{TB: t}
+	h := NewHarness(fake, []Expectation{
+		{Method: http.MethodPost, PathPattern: "/users", JSONBody: userPayload{Name: "John Doe"}},
+	})
+
+	resp, err := http.Post(h.URL()+"/users", "application/json", strings.NewReader(`{"name": "Jane Doe"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.True(t, fake.failed(), "expected a mismatched JSON body to report a failure")
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		"exact match": {
+			pattern: "/users/1",
+			path:    "/users/1",
+			want:    true,
+		},
+		"wildcard segment matches": {
+			pattern: "/users/*",
+			path:    "/users/42",
+			want:    true,
+		},
+		"wildcard only matches one segment": {
+			pattern: "/users/*",
+			path:    "/users/42/posts",
+			want:    false,
+		},
+		"mismatched literal segment": {
+			pattern: "/users/*",
+			path:    "/accounts/42",
+			want:    false,
+		},
+		"leading and trailing slashes ignored": {
+			pattern: "users/*",
+			path:    "/users/42/",
+			want:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPath(tt.pattern, tt.path))
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	crand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -11,15 +15,157 @@ type StringGenerator interface {
 	Generate() string
 }
 
-// NameGenerator creates Kubernetes-style names with random suffixes
+// maxNameLength is the DNS-1123 subdomain length limit that generated names
+// must fit within.
+const maxNameLength = 253
+
+// separator joins a base name to its random suffix.
+const separator = "-"
+
+// dns1123SubdomainRegexp matches a DNS-1123 subdomain: one or more
+// dot-separated labels of lowercase alphanumerics and '-', each of which
+// must start and end with an alphanumeric character.
+var dns1123SubdomainRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// NameValidator validates that a base name is fit to seed a generated name.
+type NameValidator interface {
+	ValidateBaseName(name string) error
+}
+
+// DNS1123Validator enforces DNS-1123 subdomain rules: lowercase alphanumeric,
+// '-' and '.', no label starting or ending with '-', and a maximum length.
+type DNS1123Validator struct{}
+
+// ValidateBaseName reports an error if name is not a valid DNS-1123
+// subdomain.
+func (DNS1123Validator) ValidateBaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("base name must not be empty")
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("base name %q exceeds max length %d", name, maxNameLength)
+	}
+	if !dns1123SubdomainRegexp.MatchString(name) {
+		return fmt.Errorf("base name %q is not a valid DNS-1123 subdomain", name)
+	}
+	return nil
+}
+
+// CollisionChecker reports whether a generated name is already in use, so
+// NameGenerator can retry rather than hand back a duplicate.
+type CollisionChecker interface {
+	Exists(name string) bool
+}
+
+// InMemoryCollisionChecker tracks previously generated names in memory. It's
+// useful for tests and single-process callers that don't have a backing API
+// to ask.
+type InMemoryCollisionChecker struct {
+	seen map[string]struct{}
+}
+
+// NewInMemoryCollisionChecker returns an InMemoryCollisionChecker seeded
+// with no known names.
+func NewInMemoryCollisionChecker() *InMemoryCollisionChecker {
+	return &InMemoryCollisionChecker{seen: make(map[string]struct{})}
+}
+
+// Exists reports whether name has been recorded via Add.
+func (c *InMemoryCollisionChecker) Exists(name string) bool {
+	_, ok := c.seen[name]
+	return ok
+}
+
+// Add records name as taken, so future calls to Exists report true for it.
+func (c *InMemoryCollisionChecker) Add(name string) {
+	c.seen[name] = struct{}{}
+}
+
+// NameGenerator creates Kubernetes-style names with random suffixes. A nil
+// validator falls back to DNS1123Validator, and a nil checker means no
+// generated name is ever treated as taken.
 type NameGenerator struct {
-	generator StringGenerator
+	generator  StringGenerator
+	validator  NameValidator
+	checker    CollisionChecker
+	maxRetries int
 }
 
-// GenerateName creates a name by appending a random suffix to the base name
-func (ng *NameGenerator) GenerateName(baseName string) string {
-	suffix := ng.generator.Generate()
-	return fmt.Sprintf("%s-%s", baseName, suffix)
+// GenerateName creates a name by appending a random suffix to baseName,
+// truncating baseName so the result still fits within maxNameLength. It
+// validates baseName and, if a CollisionChecker is configured, makes up to
+// maxRetries attempts in total until it produces a name that doesn't
+// already exist.
+func (ng *NameGenerator) GenerateName(baseName string) (string, error) {
+	validator := ng.validator
+	if validator == nil {
+		validator = DNS1123Validator{}
+	}
+	if err := validator.ValidateBaseName(baseName); err != nil {
+		return "", fmt.Errorf("invalid base name: %w", err)
+	}
+
+	maxRetries := ng.maxRetries
+	if maxRetries <= 0 {
+		maxRetries = 1
+	}
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		suffix := ng.generator.Generate()
+		trimmed := trimBaseName(baseName, maxNameLength-len(separator)-len(suffix))
+		name := trimmed + separator + suffix
+
+		if ng.checker == nil || !ng.checker.Exists(name) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not generate a unique name for base %q after %d attempts", baseName, maxRetries)
+}
+
+// GenerateNameWithPrefix mirrors k8s.io/apimachinery's
+// SimpleNameGenerator.GenerateName: it appends a random suffix directly to
+// prefix with no separator inserted, truncating prefix first so the result
+// still fits within maxNameLength. The suffix itself is never truncated: a
+// suffix longer than maxNameLength is returned whole, with the prefix
+// dropped entirely. Callers that want a "-" between prefix
+// and suffix should include the trailing dash in prefix themselves (e.g.
+// "my-pod-"). Unlike GenerateName, prefix is not validated, matching
+// apimachinery's behavior of trusting the caller to pass a sane prefix.
+func (ng *NameGenerator) GenerateNameWithPrefix(prefix string) (string, error) {
+	maxRetries := ng.maxRetries
+	if maxRetries <= 0 {
+		maxRetries = 1
+	}
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		suffix := ng.generator.Generate()
+		base := prefix
+		if max := maxNameLength - len(suffix); max < 0 {
+			base = ""
+		} else if len(base) > max {
+			base = base[:max]
+		}
+		name := base + suffix
+
+		if ng.checker == nil || !ng.checker.Exists(name) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not generate a unique name for prefix %q after %d attempts", prefix, maxRetries)
+}
+
+// trimBaseName truncates name to at most maxLen characters, then trims any
+// trailing "-" or "." left by the cut so the result still satisfies DNS-1123.
+func trimBaseName(name string, maxLen int) string {
+	if maxLen < 0 {
+		maxLen = 0
+	}
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	return strings.TrimRight(name, "-.")
 }
 
 // RandomStringGenerator generates random alphanumeric strings
@@ -39,6 +185,39 @@ func (r *RandomStringGenerator) Generate() string {
 	return string(suffix)
 }
 
+// base36Charset is the alphabet used by Base36StringGenerator.
+const base36Charset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Base36StringGenerator generates random lowercase base-36 strings using
+// crypto/rand, for callers that need an unbiased selection rather than
+// math/rand's PRNG (e.g. names with security-sensitive uniqueness
+// requirements).
+type Base36StringGenerator struct {
+	// Length is the number of characters to generate. Zero means 5.
+	Length int
+}
+
+// Generate creates a random base-36 string of g.Length characters.
+func (g Base36StringGenerator) Generate() string {
+	length := g.Length
+	if length <= 0 {
+		length = 5
+	}
+
+	max := big.NewInt(int64(len(base36Charset)))
+	suffix := make([]byte, length)
+	for i := range suffix {
+		n, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			// crypto/rand.Reader isn't expected to fail; if it does, there's
+			// no sensible source of randomness left to fall back to.
+			panic(fmt.Sprintf("crypto/rand failed: %v", err))
+		}
+		suffix[i] = base36Charset[n.Int64()]
+	}
+	return string(suffix)
+}
+
 func main() {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	strGen := &RandomStringGenerator{rng: rng}
@@ -46,7 +225,16 @@ func main() {
 
 	// Generate some k8s-style names
 	fmt.Println("Generated names:")
-	fmt.Printf("  Pod:        %s\n", nameGen.GenerateName("my-pod"))
-	fmt.Printf("  Deployment: %s\n", nameGen.GenerateName("nginx-deployment"))
-	fmt.Printf("  Service:    %s\n", nameGen.GenerateName("api-service"))
+	printGeneratedName(nameGen, "Pod:        ", "my-pod")
+	printGeneratedName(nameGen, "Deployment: ", "nginx-deployment")
+	printGeneratedName(nameGen, "Service:    ", "api-service")
+}
+
+func printGeneratedName(nameGen *NameGenerator, label, baseName string) {
+	name, err := nameGen.GenerateName(baseName)
+	if err != nil {
+		fmt.Printf("  %s error: %v\n", label, err)
+		return
+	}
+	fmt.Printf("  %s%s\n", label, name)
 }
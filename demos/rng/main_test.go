@@ -1,11 +1,52 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func TestBase36StringGenerator_Generate(t *testing.T) {
+	tests := map[string]struct {
+		length         int
+		expectedLength int
+	}{
+		"explicit length": {
+			length:         10,
+			expectedLength: 10,
+		},
+		"zero length defaults to 5": {
+			length:         0,
+			expectedLength: 5,
+		},
+		"negative length defaults to 5": {
+			length:         -1,
+			expectedLength: 5,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gen := Base36StringGenerator{Length: tt.length}
+
+			result := gen.Generate()
+
+			assert.Len(t, result, tt.expectedLength)
+			for _, r := range result {
+				assert.Contains(t, base36Charset, string(r))
+			}
+		})
+	}
+}
+
+func TestBase36StringGenerator_GeneratesDifferentValues(t *testing.T) {
+	gen := Base36StringGenerator{Length: 20}
+
+	assert.NotEqual(t, gen.Generate(), gen.Generate())
+}
+
 // mockStringGenerator returns a predetermined string
 type mockStringGenerator struct {
 	value string
@@ -15,6 +56,20 @@ func (m *mockStringGenerator) Generate() string {
 	return m.value
 }
 
+// sequenceStringGenerator returns successive values from a fixed list, for
+// tests that need the generator to produce different suffixes across
+// retries.
+type sequenceStringGenerator struct {
+	values []string
+	calls  int
+}
+
+func (s *sequenceStringGenerator) Generate() string {
+	v := s.values[s.calls]
+	s.calls++
+	return v
+}
+
 func TestGenerateName(t *testing.T) {
 	tests := map[string]struct {
 		baseName     string
@@ -48,9 +103,98 @@ func TestGenerateName(t *testing.T) {
 			mock := &mockStringGenerator{value: tt.suffix}
 			generator := &NameGenerator{generator: mock}
 
-			result := generator.GenerateName(tt.baseName)
+			result, err := generator.GenerateName(tt.baseName)
 
+			require.NoError(t, err)
 			assert.Equal(t, tt.expectedName, result)
 		})
 	}
 }
+
+func TestGenerateName_InvalidBaseNameRejected(t *testing.T) {
+	tests := map[string]string{
+		"uppercase":     "My-Pod",
+		"leading dash":  "-my-pod",
+		"trailing dash": "my-pod-",
+		"invalid char":  "my_pod",
+		"empty":         "",
+	}
+
+	for name, baseName := range tests {
+		t.Run(name, func(t *testing.T) {
+			generator := &NameGenerator{generator: &mockStringGenerator{value: "abcde"}}
+
+			_, err := generator.GenerateName(baseName)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerateName_TruncatesLongBaseName(t *testing.T) {
+	longBase := strings.Repeat("a", maxNameLength)
+	generator := &NameGenerator{generator: &mockStringGenerator{value: "abcde"}}
+
+	result, err := generator.GenerateName(longBase)
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(result), maxNameLength)
+	assert.True(t, strings.HasSuffix(result, "-abcde"))
+	assert.False(t, strings.Contains(result, "--abcde"))
+}
+
+func TestGenerateName_RetriesOnCollision(t *testing.T) {
+	gen := &sequenceStringGenerator{values: []string{"abcde", "abcde", "fghij"}}
+	checker := NewInMemoryCollisionChecker()
+	checker.Add("my-pod-abcde")
+
+	generator := &NameGenerator{generator: gen, checker: checker, maxRetries: 3}
+
+	result, err := generator.GenerateName("my-pod")
+
+	require.NoError(t, err)
+	assert.Equal(t, "my-pod-fghij", result)
+	assert.Equal(t, 3, gen.calls)
+}
+
+func TestGenerateName_ExhaustsRetriesOnCollision(t *testing.T) {
+	gen := &sequenceStringGenerator{values: []string{"abcde", "abcde"}}
+	checker := NewInMemoryCollisionChecker()
+	checker.Add("my-pod-abcde")
+
+	generator := &NameGenerator{generator: gen, checker: checker, maxRetries: 2}
+
+	_, err := generator.GenerateName("my-pod")
+
+	assert.ErrorContains(t, err, "could not generate a unique name")
+}
+
+func TestGenerateNameWithPrefix(t *testing.T) {
+	generator := &NameGenerator{generator: &mockStringGenerator{value: "abcde"}}
+
+	result, err := generator.GenerateNameWithPrefix("my-pod-")
+
+	require.NoError(t, err)
+	assert.Equal(t, "my-pod-abcde", result)
+}
+
+func TestGenerateNameWithPrefix_TruncatesLongPrefix(t *testing.T) {
+	longPrefix := strings.Repeat("a", maxNameLength)
+	generator := &NameGenerator{generator: &mockStringGenerator{value: "abcde"}}
+
+	result, err := generator.GenerateNameWithPrefix(longPrefix)
+
+	require.NoError(t, err)
+	assert.Len(t, result, maxNameLength)
+	assert.True(t, strings.HasSuffix(result, "abcde"))
+}
+
+func TestGenerateNameWithPrefix_SuffixLongerThanMaxNameLengthDoesNotPanic(t *testing.T) {
+	longSuffix := strings.Repeat("a", maxNameLength+50)
+	generator := &NameGenerator{generator: &mockStringGenerator{value: longSuffix}}
+
+	result, err := generator.GenerateNameWithPrefix("foo-")
+
+	require.NoError(t, err)
+	assert.Equal(t, longSuffix, result)
+}
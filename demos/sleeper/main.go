@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"time"
+
+	"github.com/ian-howell/implicit-interfacing/logging"
 )
 
 // Sleeper interface abstracts time.Sleep
@@ -13,17 +17,37 @@ type Sleeper interface {
 // Worker handles operations that take time
 type Worker struct {
 	sleeper Sleeper
+	logger  logging.Logger
 }
 
-func NewWorker(sleeper Sleeper) *Worker {
-	return &Worker{sleeper: sleeper}
+// WorkerOption configures a Worker constructed via NewWorker.
+type WorkerOption func(*Worker)
+
+// WithWorkerLogger configures the Logger a Worker uses to emit structured
+// events, overridden per call by any Logger attached to DoWork's context.
+func WithWorkerLogger(logger logging.Logger) WorkerOption {
+	return func(w *Worker) { w.logger = logger }
 }
 
-// DoWork simulates work that takes time
-func (w *Worker) DoWork() {
-	fmt.Println("Starting work...")
+// NewWorker constructs a Worker that sleeps via sleeper. By default it emits
+// no log events; pass WithWorkerLogger to change that.
+func NewWorker(sleeper Sleeper, opts ...WorkerOption) *Worker {
+	w := &Worker{sleeper: sleeper, logger: logging.Noop}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// DoWork simulates work that takes time, emitting worker.sleep.start and
+// worker.sleep.end events through ctx's Logger (if any) or w's configured
+// Logger.
+func (w *Worker) DoWork(ctx context.Context) {
+	logger := logging.OrDefault(ctx, w.logger)
+
+	logger.Log(logging.LevelInfo, "worker.sleep.start", "duration", 2*time.Second)
 	w.sleeper.Sleep(2 * time.Second)
-	fmt.Println("Work complete!")
+	logger.Log(logging.LevelInfo, "worker.sleep.end")
 }
 
 type realSleeper struct{}
@@ -36,8 +60,9 @@ func main() {
 	start := time.Now()
 	fmt.Printf("Started at: %s\n", start.Format("15:04:05"))
 
-	worker := NewWorker(realSleeper{})
-	worker.DoWork()
+	logger := logging.NewStdlibLogger(log.Default())
+	worker := NewWorker(realSleeper{}, WithWorkerLogger(logger))
+	worker.DoWork(context.Background())
 
 	end := time.Now()
 	fmt.Printf("Finished at: %s\n", end.Format("15:04:05"))
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ian-howell/implicit-interfacing/logging"
 )
 
 // mockSleeper records sleep duration without actually sleeping
@@ -21,7 +25,32 @@ func TestDoWork(t *testing.T) {
 	mock := &mockSleeper{}
 	worker := NewWorker(mock)
 
-	worker.DoWork()
+	worker.DoWork(context.Background())
 
 	assert.Equal(t, 2*time.Second, mock.duration)
 }
+
+func TestDoWork_LogsSleepEvents(t *testing.T) {
+	mock := &mockSleeper{}
+	recorder := &logging.Recorder{}
+	worker := NewWorker(mock, WithWorkerLogger(recorder))
+
+	worker.DoWork(context.Background())
+
+	require.Len(t, recorder.Entries, 2)
+	assert.Equal(t, "worker.sleep.start", recorder.Entries[0].Msg)
+	assert.Equal(t, []any{"duration", 2 * time.Second}, recorder.Entries[0].KV)
+	assert.Equal(t, "worker.sleep.end", recorder.Entries[1].Msg)
+}
+
+func TestDoWork_ContextLoggerOverridesWorkerLogger(t *testing.T) {
+	mock := &mockSleeper{}
+	workerLogger := &logging.Recorder{}
+	ctxLogger := &logging.Recorder{}
+	worker := NewWorker(mock, WithWorkerLogger(workerLogger))
+
+	worker.DoWork(logging.WithLogger(context.Background(), ctxLogger))
+
+	assert.Empty(t, workerLogger.Entries)
+	require.Len(t, ctxLogger.Entries, 2)
+}
@@ -0,0 +1,93 @@
+// Package logging provides a small leveled, structured Logger interface,
+// in the spirit of go-kit's leveled logger, shared by the demos in this
+// repo so each one doesn't have to redeclare it.
+package logging
+
+import (
+	"context"
+	"log"
+)
+
+// Level indicates the severity of a logged event.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the lowercase name of l, for use in log output.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Logger emits leveled, structured events: a message plus alternating
+// key/value pairs.
+type Logger interface {
+	Log(level Level, msg string, kv ...any)
+}
+
+// noopLogger discards every event.
+type noopLogger struct{}
+
+func (noopLogger) Log(Level, string, ...any) {}
+
+// Noop is a Logger that discards every event. It's the sensible default for
+// callers that haven't configured a Logger.
+var Noop Logger = noopLogger{}
+
+// stdlibLogger adapts a *log.Logger to the Logger interface.
+type stdlibLogger struct {
+	logger *log.Logger
+}
+
+// NewStdlibLogger wraps logger as a Logger, printing one space-separated
+// line per event: "level <level> msg <msg>" followed by the supplied keys
+// and values.
+func NewStdlibLogger(logger *log.Logger) Logger {
+	return &stdlibLogger{logger: logger}
+}
+
+func (s *stdlibLogger) Log(level Level, msg string, kv ...any) {
+	args := append([]any{"level", level.String(), "msg", msg}, kv...)
+	s.logger.Println(args...)
+}
+
+// contextKey is the context key under which WithLogger stores a Logger.
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, retrievable via
+// FromContext. It lets code deep in a call chain attach request-scoped
+// fields (e.g. a request ID) without threading a Logger through every
+// signature.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the Logger attached to ctx via WithLogger, or Noop if
+// none is attached.
+func FromContext(ctx context.Context) Logger {
+	return OrDefault(ctx, Noop)
+}
+
+// OrDefault returns the Logger attached to ctx via WithLogger, or fallback
+// if ctx carries none.
+func OrDefault(ctx context.Context, fallback Logger) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	return fallback
+}
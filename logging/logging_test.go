@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel_String(t *testing.T) {
+	tests := map[string]struct {
+		level Level
+		want  string
+	}{
+		"debug":         {level: LevelDebug, want: "debug"},
+		"info":          {level: LevelInfo, want: "info"},
+		"warn":          {level: LevelWarn, want: "warn"},
+		"error":         {level: LevelError, want: "error"},
+		"unknown level": {level: Level(99), want: "unknown"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.level.String())
+		})
+	}
+}
+
+func TestNoop_DoesNothing(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Noop.Log(LevelError, "should be discarded", "key", "value")
+	})
+}
+
+func TestStdlibLogger_Log(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdlibLogger(log.New(&buf, "", 0))
+
+	logger.Log(LevelWarn, "http.retry", "attempt", 1)
+
+	output := buf.String()
+	assert.Contains(t, output, "level")
+	assert.Contains(t, output, "warn")
+	assert.Contains(t, output, "http.retry")
+	assert.Contains(t, output, "attempt")
+	assert.Contains(t, output, "1")
+}
+
+func TestOrDefault(t *testing.T) {
+	fallback := &Recorder{}
+
+	result := OrDefault(context.Background(), fallback)
+
+	assert.Same(t, fallback, result)
+}
+
+func TestOrDefault_PrefersContextLogger(t *testing.T) {
+	fallback := &Recorder{}
+	ctxLogger := &Recorder{}
+	ctx := WithLogger(context.Background(), ctxLogger)
+
+	result := OrDefault(ctx, fallback)
+
+	assert.Same(t, ctxLogger, result)
+}
+
+func TestFromContext_FallsBackToNoop(t *testing.T) {
+	assert.Equal(t, Noop, FromContext(context.Background()))
+}
+
+func TestFromContext_ReturnsAttachedLogger(t *testing.T) {
+	ctxLogger := &Recorder{}
+	ctx := WithLogger(context.Background(), ctxLogger)
+
+	assert.Same(t, ctxLogger, FromContext(ctx))
+}
@@ -0,0 +1,19 @@
+package logging
+
+// Entry is one recorded call to a Recorder's Log method.
+type Entry struct {
+	Level Level
+	Msg   string
+	KV    []any
+}
+
+// Recorder is a Logger that records every event instead of emitting it
+// anywhere, for asserting on logged events in tests.
+type Recorder struct {
+	Entries []Entry
+}
+
+// Log records the event as an Entry.
+func (r *Recorder) Log(level Level, msg string, kv ...any) {
+	r.Entries = append(r.Entries, Entry{Level: level, Msg: msg, KV: kv})
+}